@@ -8,10 +8,12 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"reflect"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/go-kit/log"
 	commonconfig "github.com/prometheus/common/config"
 	promconfig "github.com/prometheus/prometheus/config"
 	promHTTP "github.com/prometheus/prometheus/discovery/http"
@@ -37,11 +39,36 @@ type Config struct {
 	// ReportExtraScrapeMetrics - enables reporting of additional metrics for Prometheus client like scrape_body_size_bytes
 	ReportExtraScrapeMetrics bool `mapstructure:"report_extra_scrape_metrics"`
 
+	// ConfigFile is the path of a Prometheus configuration file used instead of the inline config.
+	// The file is watched and its scrape configs are reloaded whenever it changes.
+	ConfigFile string `mapstructure:"config_file"`
+
+	// DrainTimeout bounds how long Shutdown waits for in-flight scrapes to flush their
+	// samples to the next consumer before the scrape manager is stopped. Samples of scrapes starting
+	// while draining are dropped. Zero disables draining.
+	DrainTimeout time.Duration `mapstructure:"drain_timeout"`
+
 	TargetAllocator *TargetAllocator `mapstructure:"target_allocator"`
 }
 
 // Validate checks the receiver configuration is valid.
 func (cfg *Config) Validate() error {
+	if cfg.DrainTimeout < 0 {
+		return fmt.Errorf("drain_timeout must not be negative, got %s", cfg.DrainTimeout)
+	}
+	if cfg.ConfigFile != "" {
+		if cfg.TargetAllocator != nil {
+			return errors.New("config_file cannot be used together with target_allocator")
+		}
+		// The config file replaces the whole inline config, including its global settings.
+		if cfg.PrometheusConfig != nil && !cfg.PrometheusConfig.isUnset() {
+			return errors.New("config_file cannot be used together with config")
+		}
+		if _, err := loadConfigFile(cfg.ConfigFile); err != nil {
+			return fmt.Errorf("invalid config_file: %w", err)
+		}
+		return nil
+	}
 	if (cfg.PrometheusConfig == nil || len(cfg.PrometheusConfig.ScrapeConfigs) == 0) && cfg.TargetAllocator == nil {
 		return errors.New("no Prometheus scrape_configs or target_allocator set")
 	}
@@ -79,9 +106,39 @@ func (cfg *PromConfig) Unmarshal(componentParser *confmap.Conf) error {
 	if len(cfgMap) == 0 {
 		return nil
 	}
+	if err := unmarshalScrapeConfigs(cfgMap); err != nil {
+		return err
+	}
 	return unmarshalYAML(cfgMap, (*promconfig.Config)(cfg))
 }
 
+// unmarshalScrapeConfigs decodes every scrape config on its own, so that errors reported by
+// Prometheus while decoding, such as conflicting credentials, name the job they come from.
+func unmarshalScrapeConfigs(cfgMap map[string]any) error {
+	scrapeConfigs, ok := cfgMap["scrape_configs"].([]any)
+	if !ok {
+		return nil
+	}
+	for i, sc := range scrapeConfigs {
+		scMap, ok := sc.(map[string]any)
+		if !ok {
+			continue
+		}
+		if err := unmarshalYAML(scMap, &promconfig.ScrapeConfig{}); err != nil {
+			if jobName, ok := scMap["job_name"].(string); ok && jobName != "" {
+				return fmt.Errorf("invalid scrape config for job %q: %w", jobName, err)
+			}
+			return fmt.Errorf("invalid scrape config scrape_configs[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// isUnset reports whether cfg was left empty or at its default value.
+func (cfg *PromConfig) isUnset() bool {
+	return reflect.DeepEqual(*cfg, PromConfig{}) || reflect.DeepEqual(*cfg, PromConfig(promconfig.DefaultConfig))
+}
+
 func (cfg *PromConfig) Validate() error {
 	// Reject features that Prometheus supports but that the receiver doesn't support:
 	// See:
@@ -109,7 +166,19 @@ func (cfg *PromConfig) Validate() error {
 		return fmt.Errorf("unsupported features:\n\t%s", strings.Join(unsupportedFeatures, "\n\t"))
 	}
 
+	jobNames := make(map[string]struct{}, len(cfg.ScrapeConfigs))
 	for _, sc := range cfg.ScrapeConfigs {
+		// The scrape manager rejects duplicate job names too, but only once the receiver starts. Report them
+		// while validating the config instead.
+		if _, ok := jobNames[sc.JobName]; ok {
+			return fmt.Errorf("found multiple scrape configs with job name %q", sc.JobName)
+		}
+		jobNames[sc.JobName] = struct{}{}
+
+		if err := checkHTTPClientCredentials(sc.HTTPClientConfig); err != nil {
+			return fmt.Errorf("invalid credentials for job %q: %w", sc.JobName, err)
+		}
+
 		if sc.HTTPClientConfig.Authorization != nil {
 			if err := checkFile(sc.HTTPClientConfig.Authorization.CredentialsFile); err != nil {
 				return fmt.Errorf("error checking authorization credentials file %q: %w", sc.HTTPClientConfig.Authorization.CredentialsFile, err)
@@ -131,6 +200,24 @@ func (cfg *PromConfig) Validate() error {
 	return nil
 }
 
+// loadConfigFile reads the Prometheus configuration file at path and rejects the
+// features the receiver doesn't support.
+func loadConfigFile(path string) (*PromConfig, error) {
+	promCfg, err := promconfig.LoadFile(path, false, false, log.NewNopLogger())
+	if err != nil {
+		return nil, err
+	}
+	cfg := (*PromConfig)(promCfg)
+	// A file being rewritten can briefly be empty, it must not remove every scrape job.
+	if len(cfg.ScrapeConfigs) == 0 {
+		return nil, errors.New("no Prometheus scrape_configs set")
+	}
+	if err = cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
 // PromHTTPSDConfig is a redeclaration of promHTTP.SDConfig because we need custom unmarshaling
 // as prometheus "config" uses `yaml` tags.
 type PromHTTPSDConfig promHTTP.SDConfig
@@ -168,6 +255,37 @@ func checkFile(fn string) error {
 	return err
 }
 
+// checkHTTPClientCredentials reports conflicting credential fields, and credential files that don't exist,
+// so that they don't only show up as failed scrapes.
+func checkHTTPClientCredentials(httpCfg commonconfig.HTTPClientConfig) error {
+	if err := checkFile(httpCfg.BearerTokenFile); err != nil {
+		return fmt.Errorf("error checking bearer token file %q: %w", httpCfg.BearerTokenFile, err)
+	}
+	if httpCfg.BasicAuth != nil {
+		if err := checkFile(httpCfg.BasicAuth.UsernameFile); err != nil {
+			return fmt.Errorf("error checking basic_auth username file %q: %w", httpCfg.BasicAuth.UsernameFile, err)
+		}
+		if err := checkFile(httpCfg.BasicAuth.PasswordFile); err != nil {
+			return fmt.Errorf("error checking basic_auth password file %q: %w", httpCfg.BasicAuth.PasswordFile, err)
+		}
+	}
+	// Validate moves the bearer_token settings to authorization and defaults the authorization type,
+	// so run it on a deep copy of the credentials to leave the receiver config untouched.
+	if httpCfg.Authorization != nil {
+		authorization := *httpCfg.Authorization
+		httpCfg.Authorization = &authorization
+	}
+	if httpCfg.BasicAuth != nil {
+		basicAuth := *httpCfg.BasicAuth
+		httpCfg.BasicAuth = &basicAuth
+	}
+	if httpCfg.OAuth2 != nil {
+		oauth2 := *httpCfg.OAuth2
+		httpCfg.OAuth2 = &oauth2
+	}
+	return httpCfg.Validate()
+}
+
 func checkTLSConfig(tlsConfig commonconfig.TLSConfig) error {
 	if err := checkFile(tlsConfig.CertFile); err != nil {
 		return fmt.Errorf("error checking client cert file %q: %w", tlsConfig.CertFile, err)
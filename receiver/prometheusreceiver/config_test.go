@@ -12,6 +12,8 @@ import (
 
 	promConfig "github.com/prometheus/common/config"
 	promModel "github.com/prometheus/common/model"
+	promconfig "github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
@@ -19,6 +21,7 @@ import (
 	"go.opentelemetry.io/collector/receiver/receivertest"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
+	"gopkg.in/yaml.v2"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal/metadata"
 )
@@ -47,6 +50,7 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, r1.TrimMetricSuffixes, true)
 	assert.Equal(t, r1.StartTimeMetricRegex, "^(.+_)*process_start_time_seconds$")
 	assert.True(t, r1.ReportExtraScrapeMetrics)
+	assert.Equal(t, 10*time.Second, r1.DrainTimeout)
 
 	assert.Equal(t, "http://my-targetallocator-service", r1.TargetAllocator.Endpoint)
 	assert.Equal(t, 30*time.Second, r1.TargetAllocator.Interval)
@@ -115,6 +119,18 @@ func TestLoadConfigFailsOnUnknownSection(t *testing.T) {
 	require.Error(t, component.UnmarshalConfig(sub, cfg))
 }
 
+func TestLoadConfigFailsOnNegativeDrainTimeout(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "invalid-config-negative-drain-timeout.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+	require.NoError(t, err)
+	require.NoError(t, component.UnmarshalConfig(sub, cfg))
+	require.ErrorContains(t, component.ValidateConfig(cfg), "drain_timeout must not be negative, got -1s")
+}
+
 func TestLoadConfigFailsOnNoPrometheusOrTAConfig(t *testing.T) {
 	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "invalid-config-prometheus-non-existent-scrape-config.yaml"))
 	require.NoError(t, err)
@@ -328,3 +344,244 @@ func TestFileSDConfigWithoutSDFile(t *testing.T) {
 
 	require.NoError(t, component.ValidateConfig(cfg))
 }
+
+func TestConfigFileValidation(t *testing.T) {
+	for _, tc := range []struct {
+		desc    string
+		cfg     *Config
+		wantErr string
+	}{
+		{
+			desc: "valid",
+			cfg: &Config{
+				PrometheusConfig: &PromConfig{},
+				ConfigFile:       filepath.Join("testdata", "prometheus-config-file.yaml"),
+			},
+		},
+		{
+			desc: "with target allocator",
+			cfg: &Config{
+				PrometheusConfig: &PromConfig{},
+				ConfigFile:       filepath.Join("testdata", "prometheus-config-file.yaml"),
+				TargetAllocator:  &TargetAllocator{},
+			},
+			wantErr: "config_file cannot be used together with target_allocator",
+		},
+		{
+			desc: "with inline scrape configs",
+			cfg: &Config{
+				PrometheusConfig: &PromConfig{
+					ScrapeConfigs: []*promconfig.ScrapeConfig{{JobName: "demo"}},
+				},
+				ConfigFile: filepath.Join("testdata", "prometheus-config-file.yaml"),
+			},
+			wantErr: "config_file cannot be used together with config",
+		},
+		{
+			desc: "with inline global config",
+			cfg: &Config{
+				PrometheusConfig: &PromConfig{
+					GlobalConfig: promconfig.GlobalConfig{
+						ExternalLabels: labels.FromStrings("cluster", "demo"),
+					},
+				},
+				ConfigFile: filepath.Join("testdata", "prometheus-config-file.yaml"),
+			},
+			wantErr: "config_file cannot be used together with config",
+		},
+		{
+			desc: "with default inline config",
+			cfg: &Config{
+				PrometheusConfig: &PromConfig{
+					GlobalConfig: promconfig.DefaultGlobalConfig,
+				},
+				ConfigFile: filepath.Join("testdata", "prometheus-config-file.yaml"),
+			},
+		},
+		{
+			desc: "non existent file",
+			cfg: &Config{
+				PrometheusConfig: &PromConfig{},
+				ConfigFile:       filepath.Join("testdata", "non-existent.yaml"),
+			},
+			wantErr: "invalid config_file",
+		},
+		{
+			desc: "no scrape configs",
+			cfg: &Config{
+				PrometheusConfig: &PromConfig{},
+				ConfigFile:       filepath.Join("testdata", "prometheus-config-file-empty.yaml"),
+			},
+			wantErr: "invalid config_file: no Prometheus scrape_configs set",
+		},
+		{
+			desc: "unsupported features",
+			cfg: &Config{
+				PrometheusConfig: &PromConfig{},
+				ConfigFile:       filepath.Join("testdata", "prometheus-config-file-unsupported-features.yaml"),
+			},
+			wantErr: "unsupported features:\n\tremote_write",
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestScrapeConfigCredentialsValidation(t *testing.T) {
+	for _, tc := range []struct {
+		desc       string
+		httpConfig promConfig.HTTPClientConfig
+		wantErr    string
+	}{
+		{
+			desc: "bearer token and bearer token file",
+			httpConfig: promConfig.HTTPClientConfig{
+				BearerToken:     "token",
+				BearerTokenFile: filepath.Join("testdata", "dummy-tls-cert-file"),
+			},
+			wantErr: `invalid credentials for job "demo": at most one of bearer_token & bearer_token_file must be configured`,
+		},
+		{
+			desc: "basic auth and bearer token",
+			httpConfig: promConfig.HTTPClientConfig{
+				BasicAuth:   &promConfig.BasicAuth{Username: "user", Password: "pass"},
+				BearerToken: "token",
+			},
+			wantErr: `invalid credentials for job "demo": at most one of basic_auth, oauth2, bearer_token & bearer_token_file must be configured`,
+		},
+		{
+			desc: "basic auth password and password file",
+			httpConfig: promConfig.HTTPClientConfig{
+				BasicAuth: &promConfig.BasicAuth{Username: "user", Password: "pass", PasswordFile: filepath.Join("testdata", "dummy-tls-cert-file")},
+			},
+			wantErr: `invalid credentials for job "demo": at most one of basic_auth password & password_file must be configured`,
+		},
+		{
+			desc: "authorization and bearer token",
+			httpConfig: promConfig.HTTPClientConfig{
+				Authorization: &promConfig.Authorization{Credentials: "creds"},
+				BearerToken:   "token",
+			},
+			wantErr: `invalid credentials for job "demo": authorization is not compatible with bearer_token & bearer_token_file`,
+		},
+		{
+			desc: "oauth2 without client id",
+			httpConfig: promConfig.HTTPClientConfig{
+				OAuth2: &promConfig.OAuth2{TokenURL: "http://localhost/token"},
+			},
+			wantErr: `invalid credentials for job "demo": oauth2 client_id must be configured`,
+		},
+		{
+			desc: "non existent bearer token file",
+			httpConfig: promConfig.HTTPClientConfig{
+				BearerTokenFile: "/nonexistentbearertokenfile",
+			},
+			wantErr: `invalid credentials for job "demo": error checking bearer token file "/nonexistentbearertokenfile"`,
+		},
+		{
+			desc: "non existent basic auth password file",
+			httpConfig: promConfig.HTTPClientConfig{
+				BasicAuth: &promConfig.BasicAuth{Username: "user", PasswordFile: "/nonexistentpasswordfile"},
+			},
+			wantErr: `invalid credentials for job "demo": error checking basic_auth password file "/nonexistentpasswordfile"`,
+		},
+		{
+			desc: "valid authorization without type",
+			httpConfig: promConfig.HTTPClientConfig{
+				Authorization: &promConfig.Authorization{Type: " ", Credentials: "creds"},
+			},
+		},
+		{
+			desc: "valid basic auth",
+			httpConfig: promConfig.HTTPClientConfig{
+				BasicAuth: &promConfig.BasicAuth{Username: "user", Password: "pass"},
+			},
+		},
+		{
+			desc: "valid bearer token",
+			httpConfig: promConfig.HTTPClientConfig{
+				BearerToken: "token",
+			},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := &PromConfig{
+				ScrapeConfigs: []*promconfig.ScrapeConfig{{JobName: "demo", HTTPClientConfig: tc.httpConfig}},
+			}
+			// Snapshot the credentials, the config shares their pointers with the test case.
+			want, err := yaml.Marshal(tc.httpConfig)
+			require.NoError(t, err)
+			err = cfg.Validate()
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				// Validation must not rewrite the configured credentials.
+				got, err := yaml.Marshal(cfg.ScrapeConfigs[0].HTTPClientConfig)
+				require.NoError(t, err)
+				assert.Equal(t, string(want), string(got))
+				return
+			}
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestDuplicateScrapeJobNames(t *testing.T) {
+	cfg := &Config{
+		PrometheusConfig: &PromConfig{
+			ScrapeConfigs: []*promconfig.ScrapeConfig{
+				{JobName: "demo"},
+				{JobName: "other"},
+				{JobName: "demo"},
+			},
+		},
+	}
+	assert.ErrorContains(t,
+		component.ValidateConfig(cfg),
+		`found multiple scrape configs with job name "demo"`)
+}
+
+func TestLoadConfigInvalidScrapeConfig(t *testing.T) {
+	for _, tc := range []struct {
+		desc     string
+		file     string
+		wantErrs []string
+	}{
+		{
+			desc: "conflicting credentials",
+			file: "invalid-config-prometheus-conflicting-credentials.yaml",
+			wantErrs: []string{
+				`invalid scrape config for job "conflicting"`,
+				"at most one of bearer_token & bearer_token_file must be configured",
+			},
+		},
+		{
+			desc: "missing job name",
+			file: "invalid-config-prometheus-missing-job-name.yaml",
+			wantErrs: []string{
+				"invalid scrape config scrape_configs[1]",
+				"job_name is empty",
+			},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			cm, err := confmaptest.LoadConf(filepath.Join("testdata", tc.file))
+			require.NoError(t, err)
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+			require.NoError(t, err)
+			err = component.UnmarshalConfig(sub, cfg)
+			for _, wantErr := range tc.wantErrs {
+				require.ErrorContains(t, err, wantErr)
+			}
+		})
+	}
+}
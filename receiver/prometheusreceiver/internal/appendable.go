@@ -6,15 +6,30 @@ package internal // import "github.com/open-telemetry/opentelemetry-collector-co
 import (
 	"context"
 	"regexp"
+	"sync"
 	"time"
 
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
 	"github.com/prometheus/prometheus/storage"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
 
+// Appendable is a storage.Appendable whose in-flight transactions can be drained
+// before the scrape manager is stopped.
+type Appendable interface {
+	storage.Appendable
+
+	// Drain makes appenders created from now on discard their samples, and waits until
+	// every appender created before the call has been committed or rolled back, or
+	// until ctx is done.
+	Drain(ctx context.Context) error
+}
+
 // appendable translates Prometheus scraping diffs into OpenTelemetry format.
 type appendable struct {
 	sink                   consumer.Metrics
@@ -27,6 +42,12 @@ type appendable struct {
 
 	settings receiver.CreateSettings
 	obsrecv  *receiverhelper.ObsReport
+
+	mu       sync.Mutex // mu protects the fields below.
+	draining bool
+	inflight int
+	// drained is closed once draining is set and no transaction is in flight.
+	drained chan struct{}
 }
 
 // NewAppendable returns a storage.Appendable instance that emits metrics to the sink.
@@ -39,7 +60,7 @@ func NewAppendable(
 	useCreatedMetric bool,
 	enableNativeHistograms bool,
 	externalLabels labels.Labels,
-	trimSuffixes bool) (storage.Appendable, error) {
+	trimSuffixes bool) (Appendable, error) {
 	var metricAdjuster MetricsAdjuster
 	if !useStartTimeMetric {
 		metricAdjuster = NewInitialPointAdjuster(set.Logger, gcInterval, useCreatedMetric)
@@ -62,9 +83,100 @@ func NewAppendable(
 		externalLabels:         externalLabels,
 		obsrecv:                obsrecv,
 		trimSuffixes:           trimSuffixes,
+		drained:                make(chan struct{}),
 	}, nil
 }
 
 func (o *appendable) Appender(ctx context.Context) storage.Appender {
-	return newTransaction(ctx, o.metricAdjuster, o.sink, o.externalLabels, o.settings, o.obsrecv, o.trimSuffixes, o.enableNativeHistograms)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.draining {
+		// Scrapes started while draining must not reach the sink anymore.
+		return discardAppender{}
+	}
+	o.inflight++
+	return &trackedTransaction{
+		transaction: newTransaction(ctx, o.metricAdjuster, o.sink, o.externalLabels, o.settings, o.obsrecv, o.trimSuffixes, o.enableNativeHistograms),
+		release:     sync.OnceFunc(o.release),
+	}
+}
+
+func (o *appendable) Drain(ctx context.Context) error {
+	o.mu.Lock()
+	if !o.draining {
+		o.draining = true
+		if o.inflight == 0 {
+			close(o.drained)
+		}
+	}
+	idle := o.inflight == 0
+	o.mu.Unlock()
+
+	// Don't let select pick ctx.Done() when there is nothing to wait for.
+	if idle {
+		return nil
+	}
+	select {
+	case <-o.drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (o *appendable) release() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.inflight--
+	if o.draining && o.inflight == 0 {
+		close(o.drained)
+	}
+}
+
+// trackedTransaction notifies the appendable once the wrapped transaction is done.
+type trackedTransaction struct {
+	*transaction
+	release func()
+}
+
+func (t *trackedTransaction) Commit() error {
+	defer t.release()
+	return t.transaction.Commit()
+}
+
+func (t *trackedTransaction) Rollback() error {
+	defer t.release()
+	return t.transaction.Rollback()
+}
+
+// discardAppender silently drops everything appended to it. Unlike an aborted transaction,
+// it doesn't make the scrape loop log append failures for every scrape until the scrape manager stops.
+type discardAppender struct{}
+
+func (discardAppender) Append(storage.SeriesRef, labels.Labels, int64, float64) (storage.SeriesRef, error) {
+	return 0, nil
+}
+
+func (discardAppender) AppendExemplar(storage.SeriesRef, labels.Labels, exemplar.Exemplar) (storage.SeriesRef, error) {
+	return 0, nil
+}
+
+func (discardAppender) AppendHistogram(storage.SeriesRef, labels.Labels, int64, *histogram.Histogram, *histogram.FloatHistogram) (storage.SeriesRef, error) {
+	return 0, nil
+}
+
+func (discardAppender) UpdateMetadata(storage.SeriesRef, labels.Labels, metadata.Metadata) (storage.SeriesRef, error) {
+	return 0, nil
+}
+
+func (discardAppender) AppendCTZeroSample(storage.SeriesRef, labels.Labels, int64, int64) (storage.SeriesRef, error) {
+	return 0, nil
+}
+
+func (discardAppender) Commit() error {
+	return nil
+}
+
+func (discardAppender) Rollback() error {
+	return nil
 }
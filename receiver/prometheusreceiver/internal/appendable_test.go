@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func newTestAppendable(t *testing.T, sink consumer.Metrics) Appendable {
+	app, err := NewAppendable(sink, receivertest.NewNopCreateSettings(), time.Minute, false, nil, false, false, labels.EmptyLabels(), false)
+	require.NoError(t, err)
+	return app
+}
+
+func TestDrainWithoutInFlightTransactions(t *testing.T) {
+	app := newTestAppendable(t, consumertest.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Nothing is in flight, so Drain must succeed even with a done context.
+	for i := 0; i < 100; i++ {
+		require.NoError(t, app.Drain(ctx))
+	}
+}
+
+func TestAppenderDiscardsSamplesWhileDraining(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	app := newTestAppendable(t, sink)
+	require.NoError(t, app.Drain(context.Background()))
+
+	// Appending must not fail, or the scrape loop logs an error for every scrape.
+	a := app.Appender(scrapeCtx)
+	_, err := a.Append(0, labels.FromStrings(model.MetricNameLabel, "counter_test", model.JobLabel, "test", model.InstanceLabel, "localhost:8080"), time.Now().UnixMilli(), 1)
+	require.NoError(t, err)
+	require.NoError(t, a.Commit())
+	require.Empty(t, sink.AllMetrics())
+}
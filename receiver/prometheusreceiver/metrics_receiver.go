@@ -12,10 +12,12 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
 	"sync"
 	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/go-kit/log"
 	"github.com/mitchellh/hashstructure/v2"
 	"github.com/prometheus/client_golang/prometheus"
@@ -37,6 +39,9 @@ import (
 const (
 	defaultGCInterval = 2 * time.Minute
 	gcIntervalDelta   = 1 * time.Minute
+
+	// configFileReloadDelay is how long the config file has to stay unchanged before it is reloaded.
+	configFileReloadDelay = 500 * time.Millisecond
 )
 
 // pReceiver is the type that provides Prometheus scraper/receiver functionality.
@@ -45,11 +50,14 @@ type pReceiver struct {
 	consumer            consumer.Metrics
 	cancelFunc          context.CancelFunc
 	targetAllocatorStop chan struct{}
+	configFileStop      chan struct{}
+	configFileWatcher   sync.WaitGroup
 	configLoaded        chan struct{}
 	loadConfigOnce      sync.Once
 
 	settings          receiver.CreateSettings
 	scrapeManager     *scrape.Manager
+	store             internal.Appendable
 	discoveryManager  *discovery.Manager
 	httpClient        *http.Client
 	registerer        prometheus.Registerer
@@ -64,6 +72,7 @@ func newPrometheusReceiver(set receiver.CreateSettings, cfg *Config, next consum
 		settings:            set,
 		configLoaded:        make(chan struct{}),
 		targetAllocatorStop: make(chan struct{}),
+		configFileStop:      make(chan struct{}),
 		registerer: prometheus.WrapRegistererWith(
 			prometheus.Labels{"receiver": set.ID.String()},
 			prometheus.DefaultRegisterer),
@@ -73,7 +82,7 @@ func newPrometheusReceiver(set receiver.CreateSettings, cfg *Config, next consum
 
 // Start is the method that starts Prometheus scraping. It
 // is controlled by having previously defined a Configuration using perhaps New.
-func (r *pReceiver) Start(ctx context.Context, host component.Host) error {
+func (r *pReceiver) Start(ctx context.Context, host component.Host) (err error) {
 	discoveryCtx, cancel := context.WithCancel(context.Background())
 	r.cancelFunc = cancel
 
@@ -81,8 +90,26 @@ func (r *pReceiver) Start(ctx context.Context, host component.Host) error {
 
 	// add scrape configs defined by the collector configs
 	baseCfg := r.cfg.PrometheusConfig
+	var watcher *fsnotify.Watcher
+	var realConfigFile string
+	if r.cfg.ConfigFile != "" {
+		// Watch the file before loading it, so that changes made while starting aren't missed.
+		if watcher, realConfigFile, err = r.watchConfigFile(); err != nil {
+			r.settings.Logger.Error("Failed to watch Prometheus config file", zap.String("config_file", r.cfg.ConfigFile), zap.Error(err))
+			return err
+		}
+		defer func() {
+			if err != nil {
+				_ = watcher.Close()
+			}
+		}()
+		if baseCfg, err = loadConfigFile(r.cfg.ConfigFile); err != nil {
+			r.settings.Logger.Error("Failed to load Prometheus config file", zap.String("config_file", r.cfg.ConfigFile), zap.Error(err))
+			return err
+		}
+	}
 
-	err := r.initPrometheusComponents(discoveryCtx, logger)
+	err = r.initPrometheusComponents(discoveryCtx, logger, baseCfg)
 	if err != nil {
 		r.settings.Logger.Error("Failed to initPrometheusComponents Prometheus components", zap.Error(err))
 		return err
@@ -107,6 +134,10 @@ func (r *pReceiver) Start(ctx context.Context, host component.Host) error {
 		}
 	}
 
+	if watcher != nil {
+		r.startConfigFileWatcher(watcher, realConfigFile)
+	}
+
 	r.loadConfigOnce.Do(func() {
 		close(r.configLoaded)
 	})
@@ -142,6 +173,80 @@ func (r *pReceiver) startTargetAllocator(allocConf *TargetAllocator, baseCfg *Pr
 	return nil
 }
 
+// watchConfigFile starts watching the config file for changes, it also returns the path the
+// config file currently resolves to.
+func (r *pReceiver) watchConfigFile() (*fsnotify.Watcher, string, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, "", err
+	}
+	// Watch the directory rather than the file: editors and k8s configmaps replace the file,
+	// and a watch on the file itself is lost once it is removed.
+	configFile := filepath.Clean(r.cfg.ConfigFile)
+	if err = watcher.Add(filepath.Dir(configFile)); err != nil {
+		_ = watcher.Close()
+		return nil, "", err
+	}
+	realConfigFile, _ := filepath.EvalSymlinks(configFile)
+	return watcher, realConfigFile, nil
+}
+
+// startConfigFileWatcher reloads the config file on every change reported by watcher, until the receiver is shut down.
+func (r *pReceiver) startConfigFileWatcher(watcher *fsnotify.Watcher, realConfigFile string) {
+	configFile := filepath.Clean(r.cfg.ConfigFile)
+	r.configFileWatcher.Add(1)
+	go func() {
+		defer r.configFileWatcher.Done()
+		defer watcher.Close()
+		// Writing a file usually takes several events, e.g. a truncate followed by writes, so wait
+		// for the file to settle instead of reloading it while it is only partially written.
+		var reload <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				// NOTE: k8s configmaps update files by swapping a symlink in the same directory,
+				// which produces no event for the file name, so also check where the file points to.
+				// SEE: https://martensson.io/go-fsnotify-and-kubernetes-configmaps/
+				currentConfigFile, _ := filepath.EvalSymlinks(configFile)
+				fileChanged := filepath.Clean(event.Name) == configFile && (event.Has(fsnotify.Write) || event.Has(fsnotify.Create))
+				linkChanged := currentConfigFile != "" && currentConfigFile != realConfigFile
+				if fileChanged || linkChanged {
+					realConfigFile = currentConfigFile
+					reload = time.After(configFileReloadDelay)
+				}
+			case <-reload:
+				reload = nil
+				r.reloadConfigFile()
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				r.settings.Logger.Error("Prometheus config file watcher failed", zap.Error(err))
+			case <-r.configFileStop:
+				return
+			}
+		}
+	}()
+}
+
+// reloadConfigFile applies the scrape configs of the config file. If the file can't be
+// loaded the previous configuration keeps being used.
+func (r *pReceiver) reloadConfigFile() {
+	cfg, err := loadConfigFile(r.cfg.ConfigFile)
+	if err != nil {
+		r.settings.Logger.Error("Failed to reload Prometheus config file, keeping the previous configuration", zap.String("config_file", r.cfg.ConfigFile), zap.Error(err))
+		return
+	}
+	if err = r.applyCfg(cfg); err != nil {
+		r.settings.Logger.Error("Failed to apply new scrape configuration", zap.Error(err))
+		return
+	}
+	r.settings.Logger.Info("Reloaded Prometheus config file", zap.String("config_file", r.cfg.ConfigFile))
+}
+
 // syncTargetAllocator request jobs from targetAllocator and update underlying receiver, if the response does not match the provided compareHash.
 // baseDiscoveryCfg can be used to provide additional ScrapeConfigs which will be added to the retrieved jobs.
 func (r *pReceiver) syncTargetAllocator(compareHash uint64, allocConf *TargetAllocator, baseCfg *PromConfig) (uint64, error) {
@@ -252,7 +357,7 @@ func (r *pReceiver) applyCfg(cfg *PromConfig) error {
 	return r.discoveryManager.ApplyConfig(discoveryCfg)
 }
 
-func (r *pReceiver) initPrometheusComponents(ctx context.Context, logger log.Logger) error {
+func (r *pReceiver) initPrometheusComponents(ctx context.Context, logger log.Logger, baseCfg *PromConfig) error {
 	// Some SD mechanisms use the "refresh" package, which has its own metrics.
 	refreshSdMetrics := discovery.NewRefreshMetrics(r.registerer)
 
@@ -287,17 +392,18 @@ func (r *pReceiver) initPrometheusComponents(ctx context.Context, logger log.Log
 	store, err := internal.NewAppendable(
 		r.consumer,
 		r.settings,
-		gcInterval(r.cfg.PrometheusConfig),
+		gcInterval(baseCfg),
 		r.cfg.UseStartTimeMetric,
 		startTimeMetricRegex,
 		useCreatedMetricGate.IsEnabled(),
 		enableNativeHistogramsGate.IsEnabled(),
-		r.cfg.PrometheusConfig.GlobalConfig.ExternalLabels,
+		baseCfg.GlobalConfig.ExternalLabels,
 		r.cfg.TrimMetricSuffixes,
 	)
 	if err != nil {
 		return err
 	}
+	r.store = store
 
 	scrapeManager, err := scrape.NewManager(&scrape.Options{
 		PassMetadataInContext: true,
@@ -349,11 +455,15 @@ func gcInterval(cfg *PromConfig) time.Duration {
 }
 
 // Shutdown stops and cancels the underlying Prometheus scrapers.
-func (r *pReceiver) Shutdown(context.Context) error {
+func (r *pReceiver) Shutdown(ctx context.Context) error {
+	// Stop reloading the config file first, a reload would restart the scrape loops being drained or stopped.
+	close(r.configFileStop)
+	r.configFileWatcher.Wait()
 	if r.cancelFunc != nil {
 		r.cancelFunc()
 	}
 	if r.scrapeManager != nil {
+		r.drainScrapes(ctx)
 		r.scrapeManager.Stop()
 	}
 	close(r.targetAllocatorStop)
@@ -362,3 +472,16 @@ func (r *pReceiver) Shutdown(context.Context) error {
 	}
 	return nil
 }
+
+// drainScrapes waits for in-flight scrapes to flush their current batch to the next consumer.
+// Stopping the scrape manager cancels them, which would drop the samples they already collected.
+func (r *pReceiver) drainScrapes(ctx context.Context) {
+	if r.cfg.DrainTimeout <= 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.DrainTimeout)
+	defer cancel()
+	if err := r.store.Drain(ctx); err != nil {
+		r.settings.Logger.Warn("Timed out draining in-flight scrapes, their samples may be lost", zap.Duration("drain_timeout", r.cfg.DrainTimeout), zap.Error(err))
+	}
+}
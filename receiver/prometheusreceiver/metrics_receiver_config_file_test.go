@@ -0,0 +1,182 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusreceiver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func scrapeConfigFileContent(target string, jobs ...string) string {
+	var sb strings.Builder
+	sb.WriteString("scrape_configs:\n")
+	for _, job := range jobs {
+		fmt.Fprintf(&sb, `- job_name: %s
+  scrape_interval: 1s
+  static_configs:
+    - targets: [%s]
+`, job, target)
+	}
+	return sb.String()
+}
+
+// replaceFile atomically replaces the content of path, the same way k8s configmaps are updated.
+func replaceFile(t *testing.T, path string, content string) {
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, []byte(content), 0600))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+func TestConfigFileReload(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte("# TYPE foo gauge\nfoo 1\n"))
+	}))
+	defer svr.Close()
+	target := strings.TrimPrefix(svr.URL, "http://")
+
+	configFile := filepath.Join(t.TempDir(), "prometheus.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(scrapeConfigFileContent(target, "foo")), 0600))
+
+	core, logs := observer.New(zap.InfoLevel)
+	set := receivertest.NewNopCreateSettings()
+	set.Logger = zap.New(core)
+	receiver := newPrometheusReceiver(set, &Config{
+		PrometheusConfig: &PromConfig{},
+		ConfigFile:       configFile,
+	}, new(consumertest.MetricsSink))
+
+	ctx := context.Background()
+	require.NoError(t, receiver.Start(ctx, componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, receiver.Shutdown(ctx))
+	})
+
+	scrapePools := func() []string {
+		pools := receiver.scrapeManager.ScrapePools()
+		sort.Strings(pools)
+		return pools
+	}
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"foo"}, scrapePools())
+	}, 30*time.Second, 100*time.Millisecond)
+
+	replaceFile(t, configFile, scrapeConfigFileContent(target, "foo", "bar"))
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"bar", "foo"}, scrapePools())
+	}, 30*time.Second, 100*time.Millisecond)
+
+	// An invalid file is ignored and the last good configuration stays active.
+	replaceFile(t, configFile, "scrape_configs: [")
+	assert.Eventually(t, func() bool {
+		return logs.FilterMessage("Failed to reload Prometheus config file, keeping the previous configuration").Len() > 0
+	}, 30*time.Second, 100*time.Millisecond)
+	assert.Equal(t, []string{"bar", "foo"}, scrapePools())
+}
+
+func TestConfigFileReloadAfterRecreate(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte("# TYPE foo gauge\nfoo 1\n"))
+	}))
+	defer svr.Close()
+	target := strings.TrimPrefix(svr.URL, "http://")
+
+	configFile := filepath.Join(t.TempDir(), "prometheus.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(scrapeConfigFileContent(target, "foo")), 0600))
+
+	receiver := newPrometheusReceiver(receivertest.NewNopCreateSettings(), &Config{
+		PrometheusConfig: &PromConfig{},
+		ConfigFile:       configFile,
+	}, new(consumertest.MetricsSink))
+
+	ctx := context.Background()
+	require.NoError(t, receiver.Start(ctx, componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, receiver.Shutdown(ctx))
+	})
+
+	scrapePools := func() []string {
+		pools := receiver.scrapeManager.ScrapePools()
+		sort.Strings(pools)
+		return pools
+	}
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"foo"}, scrapePools())
+	}, 30*time.Second, 100*time.Millisecond)
+
+	// Some editors delete the file and write a new one.
+	require.NoError(t, os.Remove(configFile))
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, os.WriteFile(configFile, []byte(scrapeConfigFileContent(target, "foo", "bar")), 0600))
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"bar", "foo"}, scrapePools())
+	}, 30*time.Second, 100*time.Millisecond)
+
+	// Later in-place edits of the recreated file are still picked up.
+	require.NoError(t, os.WriteFile(configFile, []byte(scrapeConfigFileContent(target, "foo", "baz")), 0600))
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"baz", "foo"}, scrapePools())
+	}, 30*time.Second, 100*time.Millisecond)
+}
+
+func TestConfigFileReloadAfterInPlaceEdit(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte("# TYPE foo gauge\nfoo 1\n"))
+	}))
+	defer svr.Close()
+	target := strings.TrimPrefix(svr.URL, "http://")
+
+	configFile := filepath.Join(t.TempDir(), "prometheus.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(scrapeConfigFileContent(target, "foo")), 0600))
+
+	core, logs := observer.New(zap.InfoLevel)
+	set := receivertest.NewNopCreateSettings()
+	set.Logger = zap.New(core)
+	receiver := newPrometheusReceiver(set, &Config{
+		PrometheusConfig: &PromConfig{},
+		ConfigFile:       configFile,
+	}, new(consumertest.MetricsSink))
+
+	ctx := context.Background()
+	require.NoError(t, receiver.Start(ctx, componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, receiver.Shutdown(ctx))
+	})
+
+	scrapePools := func() []string {
+		pools := receiver.scrapeManager.ScrapePools()
+		sort.Strings(pools)
+		return pools
+	}
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"foo"}, scrapePools())
+	}, 30*time.Second, 100*time.Millisecond)
+
+	// The file is briefly empty between truncating and writing it, that must not be reported as a failed reload.
+	f, err := os.OpenFile(configFile, os.O_WRONLY|os.O_TRUNC, 0600)
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+	_, err = f.WriteString(scrapeConfigFileContent(target, "foo", "bar"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"bar", "foo"}, scrapePools())
+	}, 30*time.Second, 100*time.Millisecond)
+	assert.Zero(t, logs.FilterMessage("Failed to reload Prometheus config file, keeping the previous configuration").Len())
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -20,7 +21,10 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -1640,3 +1644,86 @@ scrape_configs:
 	require.Contains(t, gotUA, set.BuildInfo.Command)
 	require.Contains(t, gotUA, set.BuildInfo.Version)
 }
+
+// newBlockingTarget returns a server whose first scrape blocks until release is closed.
+func newBlockingTarget(t *testing.T) (svr *httptest.Server, scraping <-chan struct{}, release chan struct{}) {
+	scrapingCh := make(chan struct{})
+	release = make(chan struct{})
+	var once sync.Once
+	svr = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		once.Do(func() {
+			close(scrapingCh)
+			<-release
+		})
+		_, _ = rw.Write([]byte("# TYPE foo gauge\nfoo 1\n"))
+	}))
+	// Unblock the handler before closing the server, Close waits for it to return.
+	t.Cleanup(svr.Close)
+	t.Cleanup(func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+	})
+	return svr, scrapingCh, release
+}
+
+func newDrainTestReceiver(t *testing.T, set receiver.CreateSettings, target string, drainTimeout time.Duration, sink *consumertest.MetricsSink) *pReceiver {
+	cfg, err := promConfig.Load(fmt.Sprintf(`
+scrape_configs:
+- job_name: foo
+  scrape_interval: 1s
+  scrape_timeout: 1s
+  static_configs:
+    - targets:
+      - %s
+        `, strings.TrimPrefix(target, "http://")), false, gokitlog.NewNopLogger())
+	require.NoError(t, err)
+	return newPrometheusReceiver(set, &Config{
+		PrometheusConfig: (*PromConfig)(cfg),
+		DrainTimeout:     drainTimeout,
+	}, sink)
+}
+
+func TestShutdownDrainsInFlightScrape(t *testing.T) {
+	svr, scraping, release := newBlockingTarget(t)
+	sink := new(consumertest.MetricsSink)
+	receiver := newDrainTestReceiver(t, receivertest.NewNopCreateSettings(), svr.URL, 10*time.Second, sink)
+
+	ctx := context.Background()
+	require.NoError(t, receiver.Start(ctx, componenttest.NewNopHost()))
+
+	<-scraping
+	shutdownErr := make(chan error)
+	go func() {
+		shutdownErr <- receiver.Shutdown(ctx)
+	}()
+
+	select {
+	case <-shutdownErr:
+		t.Fatal("Shutdown returned before the in-flight scrape completed")
+	case <-time.After(200 * time.Millisecond):
+	}
+	close(release)
+
+	require.NoError(t, <-shutdownErr)
+	require.NotEmpty(t, sink.AllMetrics())
+}
+
+func TestShutdownDrainTimeout(t *testing.T) {
+	svr, scraping, _ := newBlockingTarget(t)
+	core, logs := observer.New(zap.WarnLevel)
+	set := receivertest.NewNopCreateSettings()
+	set.Logger = zap.New(core)
+	sink := new(consumertest.MetricsSink)
+	receiver := newDrainTestReceiver(t, set, svr.URL, 100*time.Millisecond, sink)
+
+	ctx := context.Background()
+	require.NoError(t, receiver.Start(ctx, componenttest.NewNopHost()))
+
+	<-scraping
+	require.NoError(t, receiver.Shutdown(ctx))
+	assert.Empty(t, sink.AllMetrics())
+	assert.Equal(t, 1, logs.FilterMessage("Timed out draining in-flight scrapes, their samples may be lost").Len())
+}